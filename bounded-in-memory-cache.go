@@ -0,0 +1,281 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+var _ Cache[any] = (*BoundedInMemoryCache[any])(nil)
+
+// EvictionPolicy selects the strategy BoundedInMemoryCache uses to pick a
+// victim entry once it reaches its maximum entry count.
+type EvictionPolicy int
+
+const (
+	// PolicyLRU evicts the least recently used entry.
+	PolicyLRU EvictionPolicy = iota
+	// PolicyLFU evicts the least frequently used entry.
+	PolicyLFU
+)
+
+// BoundedInMemoryCache is an InMemoryCache sibling that caps the number of
+// entries it holds. Once the cap is reached, Set evicts one entry according
+// to its EvictionPolicy before inserting the new one, so it is safe to use
+// with unbounded key spaces without risking unbounded memory growth.
+type BoundedInMemoryCache[T any] struct {
+	timeToLive     time.Duration
+	cleaningTicker *time.Ticker
+	done           chan struct{}
+	mutex          sync.Mutex
+	isClosed       bool
+	maxEntries     int
+	policy         evictionPolicy[T]
+	hashmap        map[string]*list.Element
+	order          *list.List
+}
+
+// BoundedOption configures a BoundedInMemoryCache on construction.
+type BoundedOption[T any] func(*BoundedInMemoryCache[T])
+
+// WithMaxEntries sets the maximum number of entries the cache holds before
+// it starts evicting. A non-positive n disables the bound.
+func WithMaxEntries[T any](n int) BoundedOption[T] {
+	return func(c *BoundedInMemoryCache[T]) {
+		c.maxEntries = n
+	}
+}
+
+// WithEvictionPolicy sets the eviction strategy used once the cache is at
+// capacity. The default is PolicyLRU.
+func WithEvictionPolicy[T any](p EvictionPolicy) BoundedOption[T] {
+	return func(c *BoundedInMemoryCache[T]) {
+		switch p {
+		case PolicyLFU:
+			c.policy = lfuPolicy[T]{}
+		default:
+			c.policy = lruPolicy[T]{}
+		}
+	}
+}
+
+// NewBoundedInMemoryCache creates a BoundedInMemoryCache configured with the
+// given time to live, auto clean interval, and options.
+func NewBoundedInMemoryCache[T any](timeToLive, autoCleanInterval time.Duration, opts ...BoundedOption[T]) *BoundedInMemoryCache[T] {
+	c := &BoundedInMemoryCache[T]{
+		timeToLive:     timeToLive,
+		cleaningTicker: time.NewTicker(autoCleanInterval),
+		done:           make(chan struct{}),
+		policy:         lruPolicy[T]{},
+		hashmap:        make(map[string]*list.Element),
+		order:          list.New(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	go c.autoClean()
+	return c
+}
+
+func (c *BoundedInMemoryCache[T]) Set(ctx context.Context, key string, v T, ttl time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.isClosed {
+		return ErrCacheClosed
+	}
+	if ttl <= 0 {
+		ttl = c.timeToLive
+	}
+	if el, exists := c.hashmap[key]; exists {
+		be := el.Value.(*boundedEntry[T])
+		be.value = v
+		be.expiration = time.Now().Add(ttl)
+		c.policy.touch(c.order, el)
+		return nil
+	}
+	if c.maxEntries > 0 && len(c.hashmap) >= c.maxEntries {
+		c.evict()
+	}
+	be := &boundedEntry[T]{
+		key:        key,
+		value:      v,
+		expiration: time.Now().Add(ttl),
+	}
+	el := c.order.PushFront(be)
+	c.hashmap[key] = el
+	c.policy.touch(c.order, el)
+	return nil
+}
+
+func (c *BoundedInMemoryCache[T]) Get(ctx context.Context, key string) (T, error) {
+	var v T
+	if err := ctx.Err(); err != nil {
+		return v, err
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.isClosed {
+		return v, ErrCacheClosed
+	}
+	el, exists := c.hashmap[key]
+	if !exists {
+		return v, ErrCacheMiss
+	}
+	be := el.Value.(*boundedEntry[T])
+	if be.isExpired() {
+		return v, ErrCacheMiss
+	}
+	c.policy.touch(c.order, el)
+	return be.value, nil
+}
+
+func (c *BoundedInMemoryCache[T]) Delete(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.isClosed {
+		return ErrCacheClosed
+	}
+	el, exists := c.hashmap[key]
+	if !exists {
+		return ErrCacheMiss
+	}
+	delete(c.hashmap, key)
+	c.order.Remove(el)
+	return nil
+}
+
+func (c *BoundedInMemoryCache[T]) Has(ctx context.Context, key string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.isClosed {
+		return false, ErrCacheClosed
+	}
+	el, exists := c.hashmap[key]
+	if !exists || el.Value.(*boundedEntry[T]).isExpired() {
+		return false, nil
+	}
+	return true, nil
+}
+
+func (c *BoundedInMemoryCache[T]) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return len(c.hashmap)
+}
+
+func (c *BoundedInMemoryCache[T]) Keys() []string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	keys := make([]string, 0, len(c.hashmap))
+	for k := range c.hashmap {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+func (c *BoundedInMemoryCache[T]) Close(ctx context.Context) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.isClosed {
+		return ErrCacheClosed
+	}
+	close(c.done)
+	c.cleaningTicker.Stop()
+	c.isClosed = true
+	return nil
+}
+
+// evict removes the victim entry chosen by the configured policy. Callers
+// must hold c.mutex.
+func (c *BoundedInMemoryCache[T]) evict() {
+	el := c.policy.evict(c.order)
+	if el == nil {
+		return
+	}
+	be := el.Value.(*boundedEntry[T])
+	delete(c.hashmap, be.key)
+	c.order.Remove(el)
+}
+
+func (c *BoundedInMemoryCache[T]) autoClean() {
+	for {
+		select {
+		case <-c.cleaningTicker.C:
+			c.deleteExpiredEntries()
+		case <-c.done:
+			return
+		}
+	}
+}
+
+func (c *BoundedInMemoryCache[T]) deleteExpiredEntries() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	var next *list.Element
+	for el := c.order.Front(); el != nil; el = next {
+		next = el.Next()
+		be := el.Value.(*boundedEntry[T])
+		if be.isExpired() {
+			delete(c.hashmap, be.key)
+			c.order.Remove(el)
+		}
+	}
+}
+
+type boundedEntry[T any] struct {
+	key        string
+	value      T
+	expiration time.Time
+	frequency  int
+}
+
+func (e *boundedEntry[T]) isExpired() bool {
+	return e.expiration.Before(time.Now())
+}
+
+// evictionPolicy decides, for a list of *boundedEntry[T] elements, which
+// element is touched on access and which is evicted at capacity.
+type evictionPolicy[T any] interface {
+	touch(order *list.List, el *list.Element)
+	evict(order *list.List) *list.Element
+}
+
+// lruPolicy keeps order's front as the most recently used entry and its
+// back as the least recently used one.
+type lruPolicy[T any] struct{}
+
+func (lruPolicy[T]) touch(order *list.List, el *list.Element) {
+	order.MoveToFront(el)
+}
+
+func (lruPolicy[T]) evict(order *list.List) *list.Element {
+	return order.Back()
+}
+
+// lfuPolicy tracks a per-entry access frequency and evicts the entry with
+// the lowest frequency, scanning the list to find it.
+type lfuPolicy[T any] struct{}
+
+func (lfuPolicy[T]) touch(order *list.List, el *list.Element) {
+	el.Value.(*boundedEntry[T]).frequency++
+}
+
+func (lfuPolicy[T]) evict(order *list.List) *list.Element {
+	var victim *list.Element
+	for el := order.Front(); el != nil; el = el.Next() {
+		if victim == nil || el.Value.(*boundedEntry[T]).frequency < victim.Value.(*boundedEntry[T]).frequency {
+			victim = el
+		}
+	}
+	return victim
+}
@@ -0,0 +1,115 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBoundedInMemoryCache(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("missing entry", func(t *testing.T) {
+		cache := NewBoundedInMemoryCache[string](1*time.Hour, 1*time.Hour)
+		defer cache.Close(ctx)
+
+		value, err := cache.Get(ctx, "key")
+
+		assert.Empty(t, value)
+		assert.ErrorIs(t, err, ErrCacheMiss)
+	})
+
+	t.Run("cache hit", func(t *testing.T) {
+		cache := NewBoundedInMemoryCache[string](1*time.Hour, 1*time.Hour)
+		defer cache.Close(ctx)
+
+		cache.Set(ctx, "key", "value", 0)
+
+		value, err := cache.Get(ctx, "key")
+
+		assert.Equal(t, "value", value)
+		assert.NoError(t, err)
+	})
+
+	t.Run("evicts least recently used entry at capacity", func(t *testing.T) {
+		cache := NewBoundedInMemoryCache[string](1*time.Hour, 1*time.Hour, WithMaxEntries[string](2))
+		defer cache.Close(ctx)
+
+		cache.Set(ctx, "a", "1", 0)
+		cache.Set(ctx, "b", "2", 0)
+		cache.Get(ctx, "a") // "a" becomes most recently used, "b" becomes least recently used.
+		cache.Set(ctx, "c", "3", 0)
+
+		_, err := cache.Get(ctx, "b")
+		assert.ErrorIs(t, err, ErrCacheMiss)
+
+		value, err := cache.Get(ctx, "a")
+		assert.Equal(t, "1", value)
+		assert.NoError(t, err)
+
+		value, err = cache.Get(ctx, "c")
+		assert.Equal(t, "3", value)
+		assert.NoError(t, err)
+
+		assert.Equal(t, 2, cache.Len())
+	})
+
+	t.Run("evicts least frequently used entry at capacity", func(t *testing.T) {
+		cache := NewBoundedInMemoryCache[string](1*time.Hour, 1*time.Hour, WithMaxEntries[string](2), WithEvictionPolicy[string](PolicyLFU))
+		defer cache.Close(ctx)
+
+		cache.Set(ctx, "a", "1", 0)
+		cache.Set(ctx, "b", "2", 0)
+		cache.Get(ctx, "a")
+		cache.Get(ctx, "a") // "a" is accessed more often than "b".
+		cache.Set(ctx, "c", "3", 0)
+
+		_, err := cache.Get(ctx, "b")
+		assert.ErrorIs(t, err, ErrCacheMiss)
+
+		value, err := cache.Get(ctx, "a")
+		assert.Equal(t, "1", value)
+		assert.NoError(t, err)
+	})
+
+	t.Run("deleting an entry", func(t *testing.T) {
+		cache := NewBoundedInMemoryCache[string](1*time.Hour, 1*time.Hour)
+		defer cache.Close(ctx)
+
+		cache.Set(ctx, "key", "value", 0)
+
+		assert.NoError(t, cache.Delete(ctx, "key"))
+
+		_, err := cache.Get(ctx, "key")
+		assert.ErrorIs(t, err, ErrCacheMiss)
+	})
+
+	t.Run("has", func(t *testing.T) {
+		cache := NewBoundedInMemoryCache[string](1*time.Hour, 1*time.Hour)
+		defer cache.Close(ctx)
+
+		cache.Set(ctx, "key", "value", 0)
+
+		has, err := cache.Has(ctx, "key")
+		assert.True(t, has)
+		assert.NoError(t, err)
+
+		has, err = cache.Has(ctx, "missing")
+		assert.False(t, has)
+		assert.NoError(t, err)
+	})
+
+	t.Run("close releases resources even with an already-canceled context", func(t *testing.T) {
+		cache := NewBoundedInMemoryCache[string](1*time.Hour, 1*time.Hour)
+
+		canceledCtx, cancel := context.WithCancel(ctx)
+		cancel()
+
+		assert.NoError(t, cache.Close(canceledCtx))
+
+		_, err := cache.Get(ctx, "key")
+		assert.ErrorIs(t, err, ErrCacheClosed)
+	})
+}
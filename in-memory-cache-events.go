@@ -0,0 +1,88 @@
+package cache
+
+import (
+	"context"
+	"sync"
+)
+
+// OnInsert registers fn to be called asynchronously whenever Set stores a
+// value under a key, whether the key is new or being overwritten. Entries
+// restored by Load do not fire it.
+func (c *InMemoryCache[T]) OnInsert(fn func(key string, v T)) {
+	c.hooksMutex.Lock()
+	defer c.hooksMutex.Unlock()
+	c.onInsert = append(c.onInsert, fn)
+}
+
+// OnEvict registers fn to be called asynchronously whenever an entry is
+// removed by an explicit Delete.
+func (c *InMemoryCache[T]) OnEvict(fn func(key string, v T)) {
+	c.hooksMutex.Lock()
+	defer c.hooksMutex.Unlock()
+	c.onEvict = append(c.onEvict, fn)
+}
+
+// OnExpire registers fn to be called asynchronously whenever an entry is
+// removed because its time to live elapsed.
+func (c *InMemoryCache[T]) OnExpire(fn func(key string, v T)) {
+	c.hooksMutex.Lock()
+	defer c.hooksMutex.Unlock()
+	c.onExpire = append(c.onExpire, fn)
+}
+
+// fireAsync calls every hook in *hooks with key and v on its own goroutine,
+// so callers never block on a slow or misbehaving hook. hooks is read under
+// hooksMutex so it never races with OnInsert/OnEvict/OnExpire registering a
+// hook concurrently.
+func (c *InMemoryCache[T]) fireAsync(hooks *[]func(key string, v T), key string, v T) {
+	c.hooksMutex.RLock()
+	fns := append([]func(key string, v T){}, (*hooks)...)
+	c.hooksMutex.RUnlock()
+	for _, fn := range fns {
+		go fn(key, v)
+	}
+}
+
+// loadCall tracks a single in-flight GetOrLoad call so concurrent callers
+// for the same key share its result instead of invoking the loader more
+// than once.
+type loadCall[T any] struct {
+	wg  sync.WaitGroup
+	val T
+	err error
+}
+
+// GetOrLoad returns the value stored under key, calling loader to fetch and
+// store it on a miss. Concurrent GetOrLoad calls for the same key coalesce
+// into a single loader invocation; the other callers wait for its result.
+func (c *InMemoryCache[T]) GetOrLoad(key string, loader func(string) (T, error)) (T, error) {
+	ctx := context.Background()
+	if v, err := c.Get(ctx, key); err == nil {
+		return v, nil
+	} else if err == ErrCacheClosed {
+		return v, err
+	}
+
+	c.loadMutex.Lock()
+	if call, inflight := c.inflight[key]; inflight {
+		c.loadMutex.Unlock()
+		call.wg.Wait()
+		return call.val, call.err
+	}
+	call := &loadCall[T]{}
+	call.wg.Add(1)
+	c.inflight[key] = call
+	c.loadMutex.Unlock()
+
+	call.val, call.err = loader(key)
+	if call.err == nil {
+		c.Set(ctx, key, call.val, 0)
+	}
+
+	c.loadMutex.Lock()
+	delete(c.inflight, key)
+	c.loadMutex.Unlock()
+	call.wg.Done()
+
+	return call.val, call.err
+}
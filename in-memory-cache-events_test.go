@@ -0,0 +1,184 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInMemoryCacheEvents(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("on insert", func(t *testing.T) {
+		cache := NewInMemoryCache[string](1*time.Hour, 1*time.Hour)
+		defer cache.Close(ctx)
+
+		var got atomic.Value
+		var wg sync.WaitGroup
+		wg.Add(1)
+		cache.OnInsert(func(key string, v string) {
+			got.Store([2]string{key, v})
+			wg.Done()
+		})
+
+		cache.Set(ctx, "key", "value", 0)
+
+		wg.Wait()
+		assert.Equal(t, [2]string{"key", "value"}, got.Load())
+	})
+
+	t.Run("registering a hook concurrently with Set does not race", func(t *testing.T) {
+		cache := NewInMemoryCache[string](1*time.Hour, 1*time.Hour)
+		defer cache.Close(ctx)
+
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				cache.Set(ctx, "key", "value", 0)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				cache.OnInsert(func(key string, v string) {})
+			}
+		}()
+		wg.Wait()
+	})
+
+	t.Run("on evict", func(t *testing.T) {
+		cache := NewInMemoryCache[string](1*time.Hour, 1*time.Hour)
+		defer cache.Close(ctx)
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		var gotKey string
+		cache.OnEvict(func(key string, v string) {
+			gotKey = key
+			wg.Done()
+		})
+
+		cache.Set(ctx, "key", "value", 0)
+		cache.Delete(ctx, "key")
+
+		wg.Wait()
+		assert.Equal(t, "key", gotKey)
+	})
+
+	t.Run("on expire", func(t *testing.T) {
+		cache := NewInMemoryCache[string](1*time.Millisecond, 1*time.Millisecond)
+		defer cache.Close(ctx)
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		var gotKey string
+		cache.OnExpire(func(key string, v string) {
+			gotKey = key
+			wg.Done()
+		})
+
+		cache.Set(ctx, "key", "value", 0)
+
+		wg.Wait()
+		assert.Equal(t, "key", gotKey)
+	})
+}
+
+func TestInMemoryCache_GetOrLoad(t *testing.T) {
+	t.Run("loads on miss", func(t *testing.T) {
+		cache := NewInMemoryCache[string](1*time.Hour, 1*time.Hour)
+		defer cache.Close(context.Background())
+
+		var calls int32
+		loader := func(key string) (string, error) {
+			atomic.AddInt32(&calls, 1)
+			return "loaded " + key, nil
+		}
+
+		value, err := cache.GetOrLoad("key", loader)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "loaded key", value)
+		assert.EqualValues(t, 1, calls)
+	})
+
+	t.Run("does not reload on hit", func(t *testing.T) {
+		cache := NewInMemoryCache[string](1*time.Hour, 1*time.Hour)
+		defer cache.Close(context.Background())
+
+		cache.Set(context.Background(), "key", "cached", 0)
+
+		var calls int32
+		loader := func(key string) (string, error) {
+			atomic.AddInt32(&calls, 1)
+			return "loaded " + key, nil
+		}
+
+		value, err := cache.GetOrLoad("key", loader)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "cached", value)
+		assert.EqualValues(t, 0, calls)
+	})
+
+	t.Run("coalesces concurrent loads for the same key", func(t *testing.T) {
+		cache := NewInMemoryCache[string](1*time.Hour, 1*time.Hour)
+		defer cache.Close(context.Background())
+
+		var calls int32
+		release := make(chan struct{})
+		loader := func(key string) (string, error) {
+			atomic.AddInt32(&calls, 1)
+			<-release
+			return "loaded " + key, nil
+		}
+
+		const n = 10
+		var wg sync.WaitGroup
+		results := make([]string, n)
+		wg.Add(n)
+		for i := 0; i < n; i++ {
+			i := i
+			go func() {
+				defer wg.Done()
+				v, err := cache.GetOrLoad("key", loader)
+				assert.NoError(t, err)
+				results[i] = v
+			}()
+		}
+
+		time.Sleep(10 * time.Millisecond) // Let every goroutine join the in-flight call.
+		close(release)
+		wg.Wait()
+
+		assert.EqualValues(t, 1, calls)
+		for _, v := range results {
+			assert.Equal(t, "loaded key", v)
+		}
+	})
+
+	t.Run("propagates loader error without caching it", func(t *testing.T) {
+		cache := NewInMemoryCache[string](1*time.Hour, 1*time.Hour)
+		defer cache.Close(context.Background())
+
+		wantErr := errors.New("load failed")
+		loader := func(key string) (string, error) {
+			return "", wantErr
+		}
+
+		value, err := cache.GetOrLoad("key", loader)
+
+		assert.Empty(t, value)
+		assert.ErrorIs(t, err, wantErr)
+
+		_, err = cache.Get(context.Background(), "key")
+		assert.ErrorIs(t, err, ErrCacheMiss)
+	})
+}
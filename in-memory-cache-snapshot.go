@@ -0,0 +1,97 @@
+package cache
+
+import (
+	"container/heap"
+	"encoding/gob"
+	"io"
+	"os"
+	"time"
+)
+
+// snapshotEntry is the on-disk representation of a cache entry used by
+// Save/Load, decoupled from entry so the heap index never leaks into the
+// snapshot format.
+type snapshotEntry[T any] struct {
+	Key        string
+	Value      T
+	Expiration time.Time
+}
+
+// Save writes every non-expired entry to w as a gob stream, so the cache
+// can be restored later with Load to avoid a cold-cache stampede after a
+// restart.
+func (c *InMemoryCache[T]) Save(w io.Writer) error {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	if c.isClosed {
+		return ErrCacheClosed
+	}
+	entries := make([]snapshotEntry[T], 0, len(c.hashmap))
+	for _, e := range c.hashmap {
+		if e.IsExpired() {
+			continue
+		}
+		entries = append(entries, snapshotEntry[T]{
+			Key:        e.Key,
+			Value:      e.Value,
+			Expiration: e.Expiration,
+		})
+	}
+	return gob.NewEncoder(w).Encode(entries)
+}
+
+// Load reads a gob stream produced by Save and restores its entries,
+// skipping any whose expiration already elapsed. The expiration queue is
+// rebuilt in expiration order as entries are inserted.
+func (c *InMemoryCache[T]) Load(r io.Reader) error {
+	var entries []snapshotEntry[T]
+	if err := gob.NewDecoder(r).Decode(&entries); err != nil {
+		return err
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.isClosed {
+		return ErrCacheClosed
+	}
+	now := time.Now()
+	for _, se := range entries {
+		if !se.Expiration.After(now) {
+			continue
+		}
+		if e, exists := c.hashmap[se.Key]; exists {
+			e.Value = se.Value
+			e.Expiration = se.Expiration
+			heap.Fix(&c.queue, e.index)
+			continue
+		}
+		e := &entry[T]{
+			Key:        se.Key,
+			Value:      se.Value,
+			Expiration: se.Expiration,
+		}
+		c.hashmap[se.Key] = e
+		heap.Push(&c.queue, e)
+	}
+	return nil
+}
+
+// SaveFile writes the cache's non-expired entries to the file at path,
+// creating it if needed and truncating it otherwise.
+func (c *InMemoryCache[T]) SaveFile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return c.Save(f)
+}
+
+// LoadFile restores entries from the file at path, as written by SaveFile.
+func (c *InMemoryCache[T]) LoadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return c.Load(f)
+}
@@ -0,0 +1,92 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInMemoryCacheSnapshot(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("save and load round trip", func(t *testing.T) {
+		src := NewInMemoryCache[string](1*time.Hour, 1*time.Hour)
+		defer src.Close(ctx)
+		src.Set(ctx, "a", "1", 0)
+		src.Set(ctx, "b", "2", 0)
+
+		var buf bytes.Buffer
+		assert.NoError(t, src.Save(&buf))
+
+		dst := NewInMemoryCache[string](1*time.Hour, 1*time.Hour)
+		defer dst.Close(ctx)
+		assert.NoError(t, dst.Load(&buf))
+
+		value, err := dst.Get(ctx, "a")
+		assert.NoError(t, err)
+		assert.Equal(t, "1", value)
+
+		value, err = dst.Get(ctx, "b")
+		assert.NoError(t, err)
+		assert.Equal(t, "2", value)
+	})
+
+	t.Run("entries expired before save are not restored by load", func(t *testing.T) {
+		src := NewInMemoryCache[string](1*time.Millisecond, 1*time.Hour)
+		defer src.Close(ctx)
+		src.Set(ctx, "expired", "value", 0)
+
+		time.Sleep(10 * time.Millisecond) // Wait entry to expire.
+
+		var buf bytes.Buffer
+		assert.NoError(t, src.Save(&buf))
+
+		dst := NewInMemoryCache[string](1*time.Hour, 1*time.Hour)
+		defer dst.Close(ctx)
+		assert.NoError(t, dst.Load(&buf))
+
+		assert.Equal(t, 0, dst.Len())
+	})
+
+	t.Run("loading the same snapshot twice updates in place instead of duplicating the queue", func(t *testing.T) {
+		src := NewInMemoryCache[string](1*time.Hour, 1*time.Hour)
+		defer src.Close(ctx)
+		src.Set(ctx, "key", "value", 0)
+
+		var buf bytes.Buffer
+		assert.NoError(t, src.Save(&buf))
+
+		dst := NewInMemoryCache[string](1*time.Hour, 1*time.Hour)
+		defer dst.Close(ctx)
+		assert.NoError(t, dst.Load(bytes.NewReader(buf.Bytes())))
+		assert.NoError(t, dst.Load(bytes.NewReader(buf.Bytes())))
+
+		assert.Equal(t, 1, dst.Len())
+		assert.Equal(t, 1, len(dst.queue))
+
+		value, err := dst.Get(ctx, "key")
+		assert.NoError(t, err)
+		assert.Equal(t, "value", value)
+	})
+
+	t.Run("save file and load file round trip", func(t *testing.T) {
+		src := NewInMemoryCache[string](1*time.Hour, 1*time.Hour)
+		defer src.Close(ctx)
+		src.Set(ctx, "key", "value", 0)
+
+		path := filepath.Join(t.TempDir(), "cache.gob")
+		assert.NoError(t, src.SaveFile(path))
+
+		dst := NewInMemoryCache[string](1*time.Hour, 1*time.Hour)
+		defer dst.Close(ctx)
+		assert.NoError(t, dst.LoadFile(path))
+
+		value, err := dst.Get(ctx, "key")
+		assert.NoError(t, err)
+		assert.Equal(t, "value", value)
+	})
+}
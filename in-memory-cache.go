@@ -1,11 +1,49 @@
 package cache
 
 import (
+	"container/heap"
+	"context"
 	"errors"
 	"sync"
 	"time"
 )
 
+// ErrCacheMiss is returned when a lookup finds no value for a key, either
+// because it was never set or because it has expired.
+var ErrCacheMiss = errors.New("cache miss")
+
+// ErrCacheClosed is returned by any method called on a cache after Close
+// has been called on it.
+var ErrCacheClosed = errors.New("cache is closed")
+
+// Cache is the common behavior implemented by the caches in this package,
+// allowing callers to swap backends (in-memory, Redis, etc.) and to
+// propagate context cancellation into cache operations.
+type Cache[T any] interface {
+	// Get returns the value stored under key, or ErrCacheMiss if there is
+	// none or it has expired.
+	Get(ctx context.Context, key string) (T, error)
+	// Set stores v under key. A ttl of zero uses the cache's default time
+	// to live.
+	Set(ctx context.Context, key string, v T, ttl time.Duration) error
+	// Delete removes the entry stored under key, or returns ErrCacheMiss
+	// if there is none.
+	Delete(ctx context.Context, key string) error
+	// Has reports whether a non-expired entry is stored under key.
+	Has(ctx context.Context, key string) (bool, error)
+	// Len returns the number of entries currently stored, expired or not.
+	Len() int
+	// Keys returns the keys of the entries currently stored, expired or
+	// not.
+	Keys() []string
+	// Close releases the cache's resources. Once Close has returned, Get,
+	// Set, Delete, Has and a second Close return ErrCacheClosed; Len and
+	// Keys have no error result and keep reporting the stored entries.
+	Close(ctx context.Context) error
+}
+
+var _ Cache[any] = (*InMemoryCache[any])(nil)
+
 type InMemoryCache[T any] struct {
 	timeToLive     time.Duration
 	cleaningTicker *time.Ticker
@@ -13,7 +51,15 @@ type InMemoryCache[T any] struct {
 	mutex          sync.RWMutex
 	isClosed       bool
 	hashmap        map[string]*entry[T]
-	queue          []*entry[T]
+	queue          entryHeap[T]
+
+	hooksMutex sync.RWMutex
+	onInsert   []func(key string, v T)
+	onEvict    []func(key string, v T)
+	onExpire   []func(key string, v T)
+
+	loadMutex sync.Mutex
+	inflight  map[string]*loadCall[T]
 }
 
 func NewInMemoryCache[T any](timeToLive, autoCleanInterval time.Duration) *InMemoryCache[T] {
@@ -22,58 +68,120 @@ func NewInMemoryCache[T any](timeToLive, autoCleanInterval time.Duration) *InMem
 		cleaningTicker: time.NewTicker(autoCleanInterval),
 		done:           make(chan struct{}),
 		hashmap:        make(map[string]*entry[T]),
+		inflight:       make(map[string]*loadCall[T]),
 	}
 	go c.autoClean()
 	return c
 }
 
-func (c *InMemoryCache[T]) Set(key string, v T) {
+func (c *InMemoryCache[T]) Set(ctx context.Context, key string, v T, ttl time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
-	c.mustBeNotClosed()
-	if _, exists := c.hashmap[key]; exists {
-		// Remove entry with the same key from the queue.
-		for i := range c.queue {
-			if c.queue[i].Key == key {
-				c.queue = append(c.queue[:i], c.queue[i+1:]...)
-				break
-			}
-		}
+	if c.isClosed {
+		return ErrCacheClosed
+	}
+	if ttl <= 0 {
+		ttl = c.timeToLive
+	}
+	if e, exists := c.hashmap[key]; exists {
+		e.Value = v
+		e.Expiration = time.Now().Add(ttl)
+		heap.Fix(&c.queue, e.index)
+		c.fireAsync(&c.onInsert, key, v)
+		return nil
 	}
 	e := &entry[T]{
 		Key:        key,
 		Value:      v,
-		Expiration: time.Now().Add(c.timeToLive),
+		Expiration: time.Now().Add(ttl),
 	}
 	c.hashmap[key] = e
-	c.queue = append(c.queue, e)
+	heap.Push(&c.queue, e)
+	c.fireAsync(&c.onInsert, key, v)
+	return nil
 }
 
-func (c *InMemoryCache[T]) Get(key string) (T, error) {
+func (c *InMemoryCache[T]) Get(ctx context.Context, key string) (T, error) {
+	var v T
+	if err := ctx.Err(); err != nil {
+		return v, err
+	}
 	c.mutex.RLock()
 	defer c.mutex.RUnlock()
-	c.mustBeNotClosed()
-	var v T
+	if c.isClosed {
+		return v, ErrCacheClosed
+	}
 	e, exists := c.hashmap[key]
 	if !exists || e.IsExpired() {
-		return v, errors.New("cache miss")
+		return v, ErrCacheMiss
 	}
 	return e.Value, nil
 }
 
-func (c *InMemoryCache[T]) Close() {
+func (c *InMemoryCache[T]) Delete(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
-	c.mustBeNotClosed()
-	close(c.done)
-	c.cleaningTicker.Stop()
-	c.isClosed = true
+	if c.isClosed {
+		return ErrCacheClosed
+	}
+	e, exists := c.hashmap[key]
+	if !exists {
+		return ErrCacheMiss
+	}
+	delete(c.hashmap, key)
+	heap.Remove(&c.queue, e.index)
+	c.fireAsync(&c.onEvict, key, e.Value)
+	return nil
 }
 
-func (c *InMemoryCache[T]) mustBeNotClosed() {
+func (c *InMemoryCache[T]) Has(ctx context.Context, key string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
 	if c.isClosed {
-		panic("cache is closed")
+		return false, ErrCacheClosed
 	}
+	e, exists := c.hashmap[key]
+	if !exists || e.IsExpired() {
+		return false, nil
+	}
+	return true, nil
+}
+
+func (c *InMemoryCache[T]) Len() int {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return len(c.hashmap)
+}
+
+func (c *InMemoryCache[T]) Keys() []string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	keys := make([]string, 0, len(c.hashmap))
+	for k := range c.hashmap {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+func (c *InMemoryCache[T]) Close(ctx context.Context) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.isClosed {
+		return ErrCacheClosed
+	}
+	close(c.done)
+	c.cleaningTicker.Stop()
+	c.isClosed = true
+	return nil
 }
 
 func (c *InMemoryCache[T]) autoClean() {
@@ -88,12 +196,11 @@ func (c *InMemoryCache[T]) autoClean() {
 }
 
 func (c *InMemoryCache[T]) deleteExpiredEntries() {
-	if len(c.queue) == 0 {
-		return
-	}
 	c.mutex.Lock()
 	for len(c.queue) != 0 && c.queue[0].IsExpired() {
-		c.queue = c.queue[1:]
+		e := heap.Pop(&c.queue).(*entry[T])
+		delete(c.hashmap, e.Key)
+		c.fireAsync(&c.onExpire, e.Key, e.Value)
 	}
 	c.mutex.Unlock()
 }
@@ -102,8 +209,40 @@ type entry[T any] struct {
 	Key        string
 	Value      T
 	Expiration time.Time
+	index      int
 }
 
-func (e entry[T]) IsExpired() bool {
+func (e *entry[T]) IsExpired() bool {
 	return e.Expiration.Before(time.Now())
 }
+
+// entryHeap is a min-heap of *entry[T] ordered by Expiration, giving O(log n)
+// insertion, update, and removal instead of the O(n) scan-and-splice a plain
+// slice would need.
+type entryHeap[T any] []*entry[T]
+
+func (h entryHeap[T]) Len() int { return len(h) }
+
+func (h entryHeap[T]) Less(i, j int) bool { return h[i].Expiration.Before(h[j].Expiration) }
+
+func (h entryHeap[T]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *entryHeap[T]) Push(x any) {
+	e := x.(*entry[T])
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *entryHeap[T]) Pop() any {
+	old := *h
+	n := len(old)
+	e := old[n-1]
+	old[n-1] = nil
+	e.index = -1
+	*h = old[:n-1]
+	return e
+}
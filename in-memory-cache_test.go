@@ -1,6 +1,8 @@
 package cache
 
 import (
+	"container/heap"
+	"context"
 	"testing"
 	"time"
 
@@ -27,56 +29,90 @@ func TestEntry(t *testing.T) {
 	})
 }
 
+func TestEntryHeap(t *testing.T) {
+	t.Run("pops entries in expiration order", func(t *testing.T) {
+		now := time.Now()
+		h := entryHeap[string]{}
+		heap.Push(&h, &entry[string]{Key: "c", Expiration: now.Add(3 * time.Hour)})
+		heap.Push(&h, &entry[string]{Key: "a", Expiration: now.Add(1 * time.Hour)})
+		heap.Push(&h, &entry[string]{Key: "b", Expiration: now.Add(2 * time.Hour)})
+
+		var popped []string
+		for h.Len() != 0 {
+			popped = append(popped, heap.Pop(&h).(*entry[string]).Key)
+		}
+
+		assert.Equal(t, []string{"a", "b", "c"}, popped)
+	})
+
+	t.Run("keeps the min at the root after removing an arbitrary entry", func(t *testing.T) {
+		now := time.Now()
+		h := entryHeap[string]{}
+		a := &entry[string]{Key: "a", Expiration: now.Add(1 * time.Hour)}
+		b := &entry[string]{Key: "b", Expiration: now.Add(2 * time.Hour)}
+		c := &entry[string]{Key: "c", Expiration: now.Add(3 * time.Hour)}
+		heap.Push(&h, a)
+		heap.Push(&h, b)
+		heap.Push(&h, c)
+
+		heap.Remove(&h, b.index)
+
+		assert.Equal(t, "a", h[0].Key)
+	})
+}
+
 func TestInMemoryCache(t *testing.T) {
+	ctx := context.Background()
+
 	t.Run("missing entry", func(t *testing.T) {
 		cache := NewInMemoryCache[string](1*time.Hour, 1*time.Hour)
-		defer cache.Close()
+		defer cache.Close(ctx)
 
-		value, err := cache.Get("key")
+		value, err := cache.Get(ctx, "key")
 
 		assert.Empty(t, value)
-		assert.Error(t, err)
+		assert.ErrorIs(t, err, ErrCacheMiss)
 	})
 
 	t.Run("expired entry", func(t *testing.T) {
 		timeToLive := 1 * time.Millisecond
 		cleaningInterval := 1 * time.Hour
 		cache := NewInMemoryCache[string](timeToLive, cleaningInterval)
-		defer cache.Close()
+		defer cache.Close(ctx)
 
-		cache.Set("key", "value")
+		cache.Set(ctx, "key", "value", 0)
 
 		time.Sleep(10 * time.Millisecond) // Wait entry to expire.
 
-		value, err := cache.Get("key")
+		value, err := cache.Get(ctx, "key")
 
 		assert.Empty(t, value)
-		assert.Error(t, err)
+		assert.ErrorIs(t, err, ErrCacheMiss)
 	})
 
 	t.Run("deleted expired entry", func(t *testing.T) {
 		timeToLive := 1 * time.Millisecond
 		cleaningInterval := 1 * time.Millisecond
 		cache := NewInMemoryCache[string](timeToLive, cleaningInterval)
-		defer cache.Close()
+		defer cache.Close(ctx)
 
-		cache.Set("key", "value")
+		cache.Set(ctx, "key", "value", 0)
 
 		time.Sleep(10 * time.Millisecond) // Wait entry to expire and be deleted.
 
-		value, err := cache.Get("key")
+		value, err := cache.Get(ctx, "key")
 
 		assert.Empty(t, value)
-		assert.Error(t, err)
+		assert.ErrorIs(t, err, ErrCacheMiss)
 	})
 
 	t.Run("cache hit", func(t *testing.T) {
 		cache := NewInMemoryCache[string](1*time.Hour, 1*time.Hour)
-		defer cache.Close()
+		defer cache.Close(ctx)
 
-		cache.Set("key", "value")
+		cache.Set(ctx, "key", "value", 0)
 
-		value, err := cache.Get("key")
+		value, err := cache.Get(ctx, "key")
 
 		assert.Equal(t, "value", value)
 		assert.NoError(t, err)
@@ -84,24 +120,99 @@ func TestInMemoryCache(t *testing.T) {
 
 	t.Run("updating an entry", func(t *testing.T) {
 		cache := NewInMemoryCache[string](1*time.Hour, 1*time.Hour)
-		defer cache.Close()
+		defer cache.Close(ctx)
 
-		cache.Set("key", "value")
-		cache.Set("key", "updated value")
+		cache.Set(ctx, "key", "value", 0)
+		cache.Set(ctx, "key", "updated value", 0)
 
-		value, err := cache.Get("key")
+		value, err := cache.Get(ctx, "key")
 
 		assert.Equal(t, "updated value", value)
 		assert.NoError(t, err)
 	})
 
+	t.Run("per-call ttl override", func(t *testing.T) {
+		cache := NewInMemoryCache[string](1*time.Hour, 1*time.Hour)
+		defer cache.Close(ctx)
+
+		cache.Set(ctx, "key", "value", 1*time.Millisecond)
+
+		time.Sleep(10 * time.Millisecond) // Wait entry to expire.
+
+		value, err := cache.Get(ctx, "key")
+
+		assert.Empty(t, value)
+		assert.ErrorIs(t, err, ErrCacheMiss)
+	})
+
+	t.Run("deleting an entry", func(t *testing.T) {
+		cache := NewInMemoryCache[string](1*time.Hour, 1*time.Hour)
+		defer cache.Close(ctx)
+
+		cache.Set(ctx, "key", "value", 0)
+
+		err := cache.Delete(ctx, "key")
+		assert.NoError(t, err)
+
+		_, err = cache.Get(ctx, "key")
+		assert.ErrorIs(t, err, ErrCacheMiss)
+	})
+
+	t.Run("deleting a missing entry", func(t *testing.T) {
+		cache := NewInMemoryCache[string](1*time.Hour, 1*time.Hour)
+		defer cache.Close(ctx)
+
+		err := cache.Delete(ctx, "key")
+
+		assert.ErrorIs(t, err, ErrCacheMiss)
+	})
+
+	t.Run("has", func(t *testing.T) {
+		cache := NewInMemoryCache[string](1*time.Hour, 1*time.Hour)
+		defer cache.Close(ctx)
+
+		cache.Set(ctx, "key", "value", 0)
+
+		has, err := cache.Has(ctx, "key")
+		assert.True(t, has)
+		assert.NoError(t, err)
+
+		has, err = cache.Has(ctx, "missing")
+		assert.False(t, has)
+		assert.NoError(t, err)
+	})
+
+	t.Run("len and keys", func(t *testing.T) {
+		cache := NewInMemoryCache[string](1*time.Hour, 1*time.Hour)
+		defer cache.Close(ctx)
+
+		cache.Set(ctx, "a", "1", 0)
+		cache.Set(ctx, "b", "2", 0)
+
+		assert.Equal(t, 2, cache.Len())
+		assert.ElementsMatch(t, []string{"a", "b"}, cache.Keys())
+	})
+
 	t.Run("using a closed cache", func(t *testing.T) {
 		cache := NewInMemoryCache[string](1*time.Hour, 1*time.Hour)
 
-		cache.Close()
+		cache.Close(ctx)
+
+		assert.ErrorIs(t, cache.Set(ctx, "key", "value", 0), ErrCacheClosed)
+		_, err := cache.Get(ctx, "key")
+		assert.ErrorIs(t, err, ErrCacheClosed)
+		assert.ErrorIs(t, cache.Close(ctx), ErrCacheClosed)
+	})
+
+	t.Run("close releases resources even with an already-canceled context", func(t *testing.T) {
+		cache := NewInMemoryCache[string](1*time.Hour, 1*time.Hour)
+
+		canceledCtx, cancel := context.WithCancel(ctx)
+		cancel()
+
+		assert.NoError(t, cache.Close(canceledCtx))
 
-		assert.PanicsWithValue(t, "cache is closed", func() { cache.Set("key", "value") })
-		assert.PanicsWithValue(t, "cache is closed", func() { cache.Get("key") })
-		assert.PanicsWithValue(t, "cache is closed", func() { cache.Close() })
+		_, err := cache.Get(ctx, "key")
+		assert.ErrorIs(t, err, ErrCacheClosed)
 	})
 }
@@ -0,0 +1,95 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"time"
+)
+
+var _ Cache[any] = (*ShardedInMemoryCache[any])(nil)
+
+// ShardedInMemoryCache partitions keys across a fixed number of independent
+// InMemoryCache shards, each with its own mutex and cleaning goroutine. This
+// spreads lock contention across shards instead of serializing every Set and
+// Get behind a single global mutex, which matters under concurrent load.
+type ShardedInMemoryCache[T any] struct {
+	shards []*InMemoryCache[T]
+}
+
+// NewShardedInMemoryCache creates a ShardedInMemoryCache with the given
+// number of shards. Each shard is an independent InMemoryCache configured
+// with timeToLive and autoCleanInterval.
+func NewShardedInMemoryCache[T any](shards int, timeToLive, autoCleanInterval time.Duration) *ShardedInMemoryCache[T] {
+	if shards < 1 {
+		shards = 1
+	}
+	c := &ShardedInMemoryCache[T]{
+		shards: make([]*InMemoryCache[T], shards),
+	}
+	for i := range c.shards {
+		c.shards[i] = NewInMemoryCache[T](timeToLive, autoCleanInterval)
+	}
+	return c
+}
+
+// Set stores v under key in the shard the key hashes to.
+func (c *ShardedInMemoryCache[T]) Set(ctx context.Context, key string, v T, ttl time.Duration) error {
+	return c.shardFor(key).Set(ctx, key, v, ttl)
+}
+
+// Get returns the value stored under key from the shard the key hashes to.
+func (c *ShardedInMemoryCache[T]) Get(ctx context.Context, key string) (T, error) {
+	return c.shardFor(key).Get(ctx, key)
+}
+
+// Delete removes the entry stored under key from the shard the key hashes
+// to.
+func (c *ShardedInMemoryCache[T]) Delete(ctx context.Context, key string) error {
+	return c.shardFor(key).Delete(ctx, key)
+}
+
+// Has reports whether a non-expired entry is stored under key.
+func (c *ShardedInMemoryCache[T]) Has(ctx context.Context, key string) (bool, error) {
+	return c.shardFor(key).Has(ctx, key)
+}
+
+// Len returns the total number of entries stored across every shard.
+func (c *ShardedInMemoryCache[T]) Len() int {
+	n := 0
+	for _, shard := range c.shards {
+		n += shard.Len()
+	}
+	return n
+}
+
+// Keys returns the keys of the entries stored across every shard.
+func (c *ShardedInMemoryCache[T]) Keys() []string {
+	var keys []string
+	for _, shard := range c.shards {
+		keys = append(keys, shard.Keys()...)
+	}
+	return keys
+}
+
+// Close closes every shard.
+func (c *ShardedInMemoryCache[T]) Close(ctx context.Context) error {
+	var errs []error
+	for _, shard := range c.shards {
+		if err := shard.Close(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (c *ShardedInMemoryCache[T]) shardFor(key string) *InMemoryCache[T] {
+	return c.shards[fnv32a(key)%uint32(len(c.shards))]
+}
+
+// fnv32a computes the 32-bit FNV-1a hash of s.
+func fnv32a(s string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(s))
+	return h.Sum32()
+}
@@ -0,0 +1,134 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShardedInMemoryCache(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("cache hit", func(t *testing.T) {
+		cache := NewShardedInMemoryCache[string](8, 1*time.Hour, 1*time.Hour)
+		defer cache.Close(ctx)
+
+		cache.Set(ctx, "key", "value", 0)
+
+		value, err := cache.Get(ctx, "key")
+
+		assert.Equal(t, "value", value)
+		assert.NoError(t, err)
+	})
+
+	t.Run("missing entry", func(t *testing.T) {
+		cache := NewShardedInMemoryCache[string](8, 1*time.Hour, 1*time.Hour)
+		defer cache.Close(ctx)
+
+		value, err := cache.Get(ctx, "key")
+
+		assert.Empty(t, value)
+		assert.ErrorIs(t, err, ErrCacheMiss)
+	})
+
+	t.Run("stores and retrieves many keys across shards", func(t *testing.T) {
+		cache := NewShardedInMemoryCache[string](4, 1*time.Hour, 1*time.Hour)
+		defer cache.Close(ctx)
+
+		for i := 0; i < 100; i++ {
+			cache.Set(ctx, strconv.Itoa(i), "value", 0)
+		}
+
+		assert.Equal(t, 100, cache.Len())
+
+		for i := 0; i < 100; i++ {
+			value, err := cache.Get(ctx, strconv.Itoa(i))
+			assert.Equal(t, "value", value)
+			assert.NoError(t, err)
+		}
+	})
+
+	t.Run("close closes every shard even if one already errors", func(t *testing.T) {
+		cache := NewShardedInMemoryCache[string](4, 1*time.Hour, 1*time.Hour)
+
+		assert.NoError(t, cache.shards[0].Close(ctx))
+
+		err := cache.Close(ctx)
+		assert.Error(t, err)
+
+		for _, shard := range cache.shards {
+			_, err := shard.Get(ctx, "key")
+			assert.ErrorIs(t, err, ErrCacheClosed)
+		}
+	})
+
+	t.Run("close releases resources even with an already-canceled context", func(t *testing.T) {
+		cache := NewShardedInMemoryCache[string](4, 1*time.Hour, 1*time.Hour)
+
+		canceledCtx, cancel := context.WithCancel(ctx)
+		cancel()
+
+		assert.NoError(t, cache.Close(canceledCtx))
+
+		for _, shard := range cache.shards {
+			_, err := shard.Get(ctx, "key")
+			assert.ErrorIs(t, err, ErrCacheClosed)
+		}
+	})
+}
+
+// runWithGoroutines splits b.N operations across exactly goroutines worker
+// goroutines. b.RunParallel's SetParallelism multiplies by GOMAXPROCS, which
+// would make the "1/8/64 goroutines" benchmark tiers lie about how many
+// goroutines they actually ran with, so this drives the count directly.
+func runWithGoroutines(b *testing.B, goroutines int, op func(i int)) {
+	var wg sync.WaitGroup
+	wg.Add(goroutines)
+	b.ResetTimer()
+	for g := 0; g < goroutines; g++ {
+		g := g
+		go func() {
+			defer wg.Done()
+			for i := g; i < b.N; i += goroutines {
+				op(i)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func benchmarkInMemoryCache(b *testing.B, goroutines int) {
+	ctx := context.Background()
+	cache := NewInMemoryCache[int](1*time.Hour, 1*time.Hour)
+	defer cache.Close(ctx)
+
+	runWithGoroutines(b, goroutines, func(i int) {
+		key := strconv.Itoa(i)
+		cache.Set(ctx, key, i, 0)
+		cache.Get(ctx, key)
+	})
+}
+
+func benchmarkShardedInMemoryCache(b *testing.B, goroutines int) {
+	ctx := context.Background()
+	cache := NewShardedInMemoryCache[int](64, 1*time.Hour, 1*time.Hour)
+	defer cache.Close(ctx)
+
+	runWithGoroutines(b, goroutines, func(i int) {
+		key := strconv.Itoa(i)
+		cache.Set(ctx, key, i, 0)
+		cache.Get(ctx, key)
+	})
+}
+
+func BenchmarkInMemoryCache_1(b *testing.B)  { benchmarkInMemoryCache(b, 1) }
+func BenchmarkInMemoryCache_8(b *testing.B)  { benchmarkInMemoryCache(b, 8) }
+func BenchmarkInMemoryCache_64(b *testing.B) { benchmarkInMemoryCache(b, 64) }
+
+func BenchmarkShardedInMemoryCache_1(b *testing.B)  { benchmarkShardedInMemoryCache(b, 1) }
+func BenchmarkShardedInMemoryCache_8(b *testing.B)  { benchmarkShardedInMemoryCache(b, 8) }
+func BenchmarkShardedInMemoryCache_64(b *testing.B) { benchmarkShardedInMemoryCache(b, 64) }